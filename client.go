@@ -15,8 +15,8 @@
 package enigma
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -30,10 +30,9 @@ const (
 	version = "v2"
 )
 
-const (
-	pollingInterval = 10 * time.Second
-	pollingTimeout  = 2 * time.Minute
-)
+// pollingTimeout bounds how long an export is polled for readiness when the
+// caller's context carries no deadline of its own.
+const pollingTimeout = 2 * time.Minute
 
 type endpoint string
 
@@ -79,9 +78,13 @@ const (
 )
 
 type query struct {
-	baseURI  string
-	datapath string
-	params   url.Values
+	baseURI    string
+	datapath   string
+	params     url.Values
+	httpClient *http.Client
+	userAgent  string
+	retry      RetryPolicy
+	err        error // set by builder methods that can fail, e.g. Filter
 }
 
 // Although used in a single location, this function has been isolated to make the code
@@ -94,36 +97,100 @@ func buildURL(baseURI, datapath string, params url.Values) string {
 	return uri
 }
 
-// doQuery performs the actual HTTP request and parses the returned JSON into a typed response structure.
-func doQuery(baseURI, datapath string, params url.Values, response interface{}) (err error) {
-	uri := buildURL(baseURI, datapath, params)
-
-	resp, err := http.Get(uri)
-	if err != nil {
-		return
+// cloneQuery returns a shallow copy of q with its own url.Values, so that callers
+// can mutate paging parameters (e.g. "page") without racing on the original query.
+func cloneQuery(q *query) *query {
+	clone := *q
+	clone.params = url.Values{}
+	for key, values := range q.params {
+		clone.params[key] = append([]string(nil), values...)
 	}
+	return &clone
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
+// fetch performs the actual HTTP request, retrying according to q.retry, and parses
+// the returned JSON into the provided response structure. It returns as soon as ctx
+// is cancelled.
+func (q *query) fetch(ctx context.Context, response interface{}) (err error) {
+	if q.err != nil {
+		return q.err
 	}
-	defer resp.Body.Close()
 
-	// API error handling
-	if resp.StatusCode != 200 {
-		var e map[string]interface{}
-		if json.Unmarshal(body, &e) != nil {
-			return errors.New(resp.Status)
+	uri := buildURL(q.baseURI, q.datapath, q.params)
+	attempts := q.retry.attempts()
+
+	var lastErr error
+	var delay time.Duration
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		req, reqErr := http.NewRequest(http.MethodGet, uri, nil)
+		if reqErr != nil {
+			return reqErr
+		}
+		req = req.WithContext(ctx)
+		if q.userAgent != "" {
+			req.Header.Set("User-Agent", q.userAgent)
+		}
+
+		resp, doErr := q.httpClient.Do(req)
+		if doErr != nil {
+			lastErr = doErr
+			delay = q.retry.nextDelay(attempt, 0)
+			continue
+		}
+
+		body, readErr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			return readErr
+		}
+
+		// API error handling
+		if resp.StatusCode != http.StatusOK {
+			apiErr := parseAPIError(resp, body)
+			if isRetryableStatus(resp.StatusCode) && attempt < attempts-1 {
+				lastErr = apiErr
+				delay = q.retry.nextDelay(attempt, RetryAfter(apiErr))
+				continue
+			}
+			return apiErr
 		}
-		return errors.New(e["info"].(map[string]interface{})["additional"].(string))
-	}
 
-	// Parsing the response into the provided response struct.
-	if err = json.Unmarshal(body, &response); err != nil {
-		return
+		return json.Unmarshal(body, response)
 	}
 
-	return
+	return lastErr
+}
+
+// isRetryableStatus reports whether a response status code is worth retrying:
+// rate limiting and server-side failures.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || (statusCode >= 500 && statusCode < 600)
+}
+
+// parseRetryAfter extracts the delay requested by a Retry-After header, supporting
+// both the delay-in-seconds and HTTP-date forms. It returns 0 if absent or invalid.
+func parseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
 }
 
 // MetaParentNodeResponse represents the structure of a metadata response describing a parent node.
@@ -200,13 +267,29 @@ type MetaQuery query
 
 // Parent metadata request for the given datapath.
 func (q *MetaQuery) Parent(datapath string) (response *MetaParentNodeResponse, err error) {
-	err = doQuery(q.baseURI, datapath, q.params, &response)
+	return q.ParentContext(context.Background(), datapath)
+}
+
+// ParentContext is the same as Parent, with the request bound to ctx so it can be
+// cancelled or given a deadline.
+func (q *MetaQuery) ParentContext(ctx context.Context, datapath string) (response *MetaParentNodeResponse, err error) {
+	wire := cloneQuery((*query)(q))
+	wire.datapath = datapath
+	err = wire.fetch(ctx, &response)
 	return
 }
 
 // Table metadata request for the given datapath.
 func (q *MetaQuery) Table(datapath string) (response *MetaTableNodeResponse, err error) {
-	err = doQuery(q.baseURI, datapath, q.params, &response)
+	return q.TableContext(context.Background(), datapath)
+}
+
+// TableContext is the same as Table, with the request bound to ctx so it can be
+// cancelled or given a deadline.
+func (q *MetaQuery) TableContext(ctx context.Context, datapath string) (response *MetaTableNodeResponse, err error) {
+	wire := cloneQuery((*query)(q))
+	wire.datapath = datapath
+	err = wire.fetch(ctx, &response)
 	return
 }
 
@@ -290,6 +373,15 @@ func (q *StatsQuery) Operation(operation Operation) *StatsQuery {
 	return q
 }
 
+// Operations requests several operations in a single call, e.g. Sum and Avg
+// together. The Enigma API accepts repeated "operation" parameters.
+func (q *StatsQuery) Operations(operations ...Operation) *StatsQuery {
+	for _, operation := range operations {
+		q.params.Add("operation", string(operation))
+	}
+	return q
+}
+
 // By indicates the compound operation to run on a given pair of columns.
 // Valid compound operations are sum and avg.
 //
@@ -321,7 +413,13 @@ func (q *StatsQuery) Page(number int) *StatsQuery {
 
 // Results or error returned by the server.
 func (q *StatsQuery) Results() (response *StatsResponse, err error) {
-	err = doQuery(q.baseURI, q.datapath, q.params, &response)
+	return q.ResultsContext(context.Background())
+}
+
+// ResultsContext is the same as Results, with the request bound to ctx so it can be
+// cancelled or given a deadline.
+func (q *StatsQuery) ResultsContext(ctx context.Context) (response *StatsResponse, err error) {
+	err = (*query)(q).fetch(ctx, &response)
 	return
 }
 
@@ -404,7 +502,13 @@ func (q *DataQuery) Page(number int) *DataQuery {
 
 // Results or error returned by the server.
 func (q *DataQuery) Results() (response DataResponse, err error) {
-	err = doQuery(q.baseURI, q.datapath, q.params, &response)
+	return q.ResultsContext(context.Background())
+}
+
+// ResultsContext is the same as Results, with the request bound to ctx so it can be
+// cancelled or given a deadline.
+func (q *DataQuery) ResultsContext(ctx context.Context) (response DataResponse, err error) {
+	err = (*query)(q).fetch(ctx, &response)
 	return
 }
 
@@ -486,41 +590,103 @@ func (q *ExportQuery) Page(number int) *ExportQuery {
 // 	}
 // 	downloadUrl := <- ready
 func (q *ExportQuery) FileURL(ready chan string) (url string, err error) {
+	return q.FileURLContext(context.Background(), ready)
+}
+
+// FileURLContext is the same as FileURL, with the request and polling loop bound to
+// ctx. Cancelling ctx stops the polling goroutine started on ready's behalf instead
+// of leaving it running in the background.
+func (q *ExportQuery) FileURLContext(ctx context.Context, ready chan string) (url string, err error) {
 	var response exportResponse
-	err = doQuery(q.baseURI, q.datapath, q.params, &response)
+	err = (*query)(q).fetch(ctx, &response)
 
 	if ready != nil {
 		go func(pollingURL, downloadURL string) {
-			for interval := pollingInterval; interval < pollingTimeout; interval = interval * 2 {
-				if resp, err := http.Head(pollingURL); err == nil && resp.StatusCode == 200 {
-					ready <- downloadURL
-					break
-				}
-				time.Sleep(interval)
+			if waitErr := q.waitUntilReady(ctx, pollingURL, 0); waitErr != nil {
+				return
+			}
+			select {
+			case ready <- downloadURL:
+			case <-ctx.Done():
 			}
 		}(response.HeadURL, response.ExportURL)
 	}
 	return response.ExportURL, err
 }
 
+// waitUntilReady polls pollingURL with HEAD requests, delaying between attempts
+// according to q.retry (the same policy applied to ordinary queries), until it
+// returns 200, ctx is cancelled, or ctx is given a deadline that elapses. If ctx
+// carries no deadline, timeout is applied instead; a zero timeout falls back to
+// pollingTimeout.
+func (q *ExportQuery) waitUntilReady(ctx context.Context, pollingURL string, timeout time.Duration) error {
+	pollCtx := ctx
+	if _, ok := ctx.Deadline(); !ok {
+		if timeout <= 0 {
+			timeout = pollingTimeout
+		}
+		var cancel context.CancelFunc
+		pollCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(http.MethodHead, pollingURL, nil)
+		if err == nil {
+			req = req.WithContext(pollCtx)
+			if resp, err := q.httpClient.Do(req); err == nil {
+				resp.Body.Close()
+				if resp.StatusCode == http.StatusOK {
+					return nil
+				}
+			}
+		}
+
+		select {
+		case <-pollCtx.Done():
+			return pollCtx.Err()
+		case <-time.After(q.retry.nextDelay(attempt, 0)):
+		}
+	}
+}
+
 // Client of the Enigma API.
 // Use NewClient to instantiate a new instance as in the following example:
 //    client := enigma.NewClient("some_api_key")
+//
+// Behaviour such as the underlying *http.Client, the User-Agent header, the base
+// URL, and the retry policy can be customized with ClientOption values.
 type Client struct {
-	key string
+	key        string
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	retry      RetryPolicy
 }
 
 // buildURI assembles the URI tho which queries should be sent.
 func (client *Client) buildURI(ep endpoint) string {
-	//<root>/<version>/<endpoint>/<api key>/<datapath>/<parameters>
-	return strings.Join([]string{root, version, string(ep), client.key}, "/")
+	//<baseURL>/<version>/<endpoint>/<api key>/<datapath>/<parameters>
+	return strings.Join([]string{client.baseURL, version, string(ep), client.key}, "/")
+}
+
+// newQuery builds the embedded query struct shared by every query type, carrying
+// over the client's HTTP client, user agent and retry policy.
+func (client *Client) newQuery(ep endpoint, datapath string) query {
+	return query{
+		baseURI:    client.buildURI(ep),
+		datapath:   datapath,
+		params:     url.Values{},
+		httpClient: client.httpClient,
+		userAgent:  client.userAgent,
+		retry:      client.retry,
+	}
 }
 
 // Meta can be used to query all datapaths for their metadata.
 func (client *Client) Meta() *MetaQuery {
-	return &MetaQuery{
-		baseURI: client.buildURI(meta),
-	}
+	q := MetaQuery(client.newQuery(meta, ""))
+	return &q
 }
 
 // Data queries the content of table datapaths.
@@ -532,11 +698,8 @@ func (client *Client) Meta() *MetaQuery {
 // Build a query by chaining up parameters, then call Results() to actually perform the query.
 //    client.Data("us.gov.whitehouse.visitor-list").Select("namefull", "appt_made_date").Sort("namefirst", enigma.Desc).Results()
 func (client *Client) Data(datapath string) *DataQuery {
-	return &DataQuery{
-		datapath: datapath,
-		params:   url.Values{},
-		baseURI:  client.buildURI(data),
-	}
+	q := DataQuery(client.newQuery(data, datapath))
+	return &q
 }
 
 // Stats queries table datapaths by column for statistics on the data they contain.
@@ -545,12 +708,8 @@ func (client *Client) Data(datapath string) *DataQuery {
 // Build a query by chaining up parameters, then call Results() to actually perform the query.
 //    client.Stats("us.gov.whitehouse.visitor-list", "total_people").Operation(enigma.Sum).Results()
 func (client *Client) Stats(datapath, column string) *StatsQuery {
-	q := &StatsQuery{
-		datapath: datapath,
-		params:   url.Values{},
-		baseURI:  client.buildURI(stats),
-	}
-	return q.selectColumn(column)
+	q := StatsQuery(client.newQuery(stats, datapath))
+	return (&q).selectColumn(column)
 }
 
 // Export requests exports of table datapaths as GZiped files.
@@ -559,16 +718,23 @@ func (client *Client) Stats(datapath, column string) *StatsQuery {
 // Build a query by chaining up parameters, then call FileURL() to perform the query and get the Url of the file to download.
 //    client.Export("us.gov.whitehouse.visitor-list").Select("namefull").Sort("namefull", Asc).FileURL(nil)
 func (client *Client) Export(datapath string) *ExportQuery {
-	return &ExportQuery{
-		datapath: datapath,
-		params:   url.Values{},
-		baseURI:  client.buildURI(export),
+	q := ExportQuery(client.newQuery(export, datapath))
+	return &q
+}
+
+// NewClient instantiates a new Client instance with a given API key, applying any
+// ClientOption values in order. Without options, the client talks to the production
+// Enigma API through http.DefaultClient, retrying transient failures with
+// DefaultRetryPolicy.
+func NewClient(key string, opts ...ClientOption) *Client {
+	client := &Client{
+		key:        key,
+		baseURL:    root,
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy(),
 	}
-}
-
-// NewClient instantiates a new Client instance with a given API key.
-func NewClient(key string) *Client {
-	return &Client{
-		key: key,
+	for _, opt := range opts {
+		opt(client)
 	}
+	return client
 }
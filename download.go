@@ -0,0 +1,217 @@
+package enigma
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// DownloadResult reports the outcome of a successful ExportQuery.Download or
+// DownloadToFile call.
+type DownloadResult struct {
+	// Bytes is the number of bytes written to the destination during this call.
+	// For a resumed download, this counts only the newly written bytes.
+	Bytes int64
+	// RowsEstimate counts newline characters seen in the written content, as a
+	// cheap approximation of the number of rows downloaded.
+	RowsEstimate int
+	// ContentSHA256 is the SHA-256 hash, hex-encoded, of the bytes written to the
+	// destination during this call. For a resumed download, it does not cover
+	// bytes written by a previous call.
+	ContentSHA256 string
+}
+
+// downloadConfig holds the options accumulated from DownloadOption values.
+type downloadConfig struct {
+	rawGzip     bool
+	progress    func(bytesDone int64)
+	resume      bool
+	pollTimeout time.Duration
+}
+
+// DownloadOption configures ExportQuery.Download and ExportQuery.DownloadToFile.
+type DownloadOption func(*downloadConfig)
+
+// WithoutDecompression writes the raw gzip stream to the destination instead of
+// transparently decompressing it.
+func WithoutDecompression() DownloadOption {
+	return func(c *downloadConfig) {
+		c.rawGzip = true
+	}
+}
+
+// WithProgress calls fn with the cumulative number of bytes written as the
+// download progresses.
+func WithProgress(fn func(bytesDone int64)) DownloadOption {
+	return func(c *downloadConfig) {
+		c.progress = fn
+	}
+}
+
+// WithResume, when the destination is an *os.File with existing content, requests
+// only the remaining bytes from the server via a Range header, appending to the
+// file instead of starting over.
+//
+// A byte range lands wherever the previous attempt stopped, which is almost never
+// a gzip block boundary, so WithResume must be combined with WithoutDecompression:
+// Download rejects the pair otherwise. Resume the raw gzip file and decompress it
+// separately once the download is complete.
+func WithResume() DownloadOption {
+	return func(c *downloadConfig) {
+		c.resume = true
+	}
+}
+
+// WithPollTimeout overrides how long Download waits for the export to become
+// ready when ctx carries no deadline of its own. It defaults to pollingTimeout.
+func WithPollTimeout(timeout time.Duration) DownloadOption {
+	return func(c *downloadConfig) {
+		c.pollTimeout = timeout
+	}
+}
+
+// countingWriter wraps a writer to count bytes, count newlines as a row estimate,
+// hash the content, and report progress.
+type countingWriter struct {
+	w        io.Writer
+	hasher   io.Writer
+	n        int64
+	rows     int
+	progress func(int64)
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	if n > 0 {
+		c.hasher.Write(p[:n])
+		c.n += int64(n)
+		for _, b := range p[:n] {
+			if b == '\n' {
+				c.rows++
+			}
+		}
+		if c.progress != nil {
+			c.progress(c.n)
+		}
+	}
+	return n, err
+}
+
+// Download requests an export, waits for it to become ready, and streams its
+// content into w, transparently gunzipping it unless WithoutDecompression is
+// passed. Waiting for the export honors ctx: if ctx carries a deadline it's used
+// as the polling timeout, otherwise WithPollTimeout applies, falling back to
+// pollingTimeout.
+//
+// WithResume requires WithoutDecompression: a byte-range request resumes in the
+// middle of the gzip stream, which gzip.NewReader can't make sense of.
+func (q *ExportQuery) Download(ctx context.Context, w io.Writer, opts ...DownloadOption) (DownloadResult, error) {
+	cfg := &downloadConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.resume && !cfg.rawGzip {
+		return DownloadResult{}, errors.New("enigma: WithResume requires WithoutDecompression, since a byte range does not land on a gzip block boundary")
+	}
+
+	var exp exportResponse
+	if err := (*query)(q).fetch(ctx, &exp); err != nil {
+		return DownloadResult{}, err
+	}
+
+	if err := q.waitUntilReady(ctx, exp.HeadURL, cfg.pollTimeout); err != nil {
+		return DownloadResult{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, exp.ExportURL, nil)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	req = req.WithContext(ctx)
+
+	if cfg.resume {
+		if f, ok := w.(*os.File); ok {
+			if info, statErr := f.Stat(); statErr == nil && info.Size() > 0 {
+				if _, err := f.Seek(0, io.SeekEnd); err != nil {
+					return DownloadResult{}, err
+				}
+				req.Header.Set("Range", fmt.Sprintf("bytes=%d-", info.Size()))
+			}
+		}
+	}
+
+	resp, err := q.httpClient.Do(req)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return DownloadResult{}, fmt.Errorf("enigma: export download failed with status %s", resp.Status)
+	}
+
+	var source io.Reader = resp.Body
+	if !cfg.rawGzip {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return DownloadResult{}, err
+		}
+		defer gz.Close()
+		source = gz
+	}
+
+	hasher := sha256.New()
+	counter := &countingWriter{w: w, hasher: hasher, progress: cfg.progress}
+	if _, err := io.Copy(counter, source); err != nil {
+		return DownloadResult{}, err
+	}
+
+	return DownloadResult{
+		Bytes:         counter.n,
+		RowsEstimate:  counter.rows,
+		ContentSHA256: hex.EncodeToString(hasher.Sum(nil)),
+	}, nil
+}
+
+// DownloadToFile is a convenience around Download that writes the export to path
+// atomically: content is streamed to a "path.tmp" file which is renamed to path
+// only once the download completes successfully.
+func (q *ExportQuery) DownloadToFile(ctx context.Context, path string, opts ...DownloadOption) (DownloadResult, error) {
+	cfg := &downloadConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	tmpPath := path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	defer f.Close()
+
+	if cfg.resume {
+		if _, err := f.Seek(0, io.SeekEnd); err != nil {
+			return DownloadResult{}, err
+		}
+	} else if err := f.Truncate(0); err != nil {
+		return DownloadResult{}, err
+	}
+
+	result, err := q.Download(ctx, f, opts...)
+	if err != nil {
+		return result, err
+	}
+
+	if err := f.Close(); err != nil {
+		return result, err
+	}
+	return result, os.Rename(tmpPath, path)
+}
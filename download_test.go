@@ -0,0 +1,203 @@
+package enigma
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newExportTestServer serves a minimal export flow: the export endpoint returns
+// export_url/head_url pointing back at the same server, HEAD always reports the
+// file ready, and GET serves gzipContent through http.ServeContent so that Range
+// requests behave like a real file server.
+func newExportTestServer(t *testing.T, gzipContent []byte) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	var serverURL string
+
+	mux.HandleFunc("/v2/export/testkey/table", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data_path":"table","export_url":%q,"head_url":%q}`,
+			serverURL+"/files/export.csv.gz", serverURL+"/files/export.csv.gz")
+	})
+	mux.HandleFunc("/files/export.csv.gz", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.ServeContent(w, r, "export.csv.gz", time.Time{}, bytes.NewReader(gzipContent))
+	})
+
+	server := httptest.NewServer(mux)
+	serverURL = server.URL
+	return server
+}
+
+func gzipBytes(t *testing.T, raw []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(raw); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestExportQueryDownloadDecompresses(t *testing.T) {
+	raw := []byte("col1,col2\nval1,val2\nval3,val4\n")
+	gz := gzipBytes(t, raw)
+
+	server := newExportTestServer(t, gz)
+	defer server.Close()
+
+	client := NewClient("testkey", WithBaseURL(server.URL), WithHTTPClient(server.Client()))
+
+	var buf bytes.Buffer
+	result, err := client.Export("table").Download(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+
+	if buf.String() != string(raw) {
+		t.Fatalf("got content %q, want %q", buf.String(), raw)
+	}
+	if result.Bytes != int64(len(raw)) {
+		t.Fatalf("Bytes = %d, want %d", result.Bytes, len(raw))
+	}
+	if want := strings.Count(string(raw), "\n"); result.RowsEstimate != want {
+		t.Fatalf("RowsEstimate = %d, want %d", result.RowsEstimate, want)
+	}
+	sum := sha256.Sum256(raw)
+	if result.ContentSHA256 != hex.EncodeToString(sum[:]) {
+		t.Fatalf("ContentSHA256 = %s, want %s", result.ContentSHA256, hex.EncodeToString(sum[:]))
+	}
+}
+
+func TestExportQueryDownloadWithoutDecompression(t *testing.T) {
+	raw := []byte("col1,col2\nval1,val2\n")
+	gz := gzipBytes(t, raw)
+
+	server := newExportTestServer(t, gz)
+	defer server.Close()
+
+	client := NewClient("testkey", WithBaseURL(server.URL), WithHTTPClient(server.Client()))
+
+	var buf bytes.Buffer
+	result, err := client.Export("table").Download(context.Background(), &buf, WithoutDecompression())
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), gz) {
+		t.Fatalf("got %d raw bytes, want the untouched gzip stream (%d bytes)", buf.Len(), len(gz))
+	}
+	if result.Bytes != int64(len(gz)) {
+		t.Fatalf("Bytes = %d, want %d", result.Bytes, len(gz))
+	}
+}
+
+func TestExportQueryDownloadRejectsResumeWithDecompression(t *testing.T) {
+	client := NewClient("testkey")
+
+	var buf bytes.Buffer
+	_, err := client.Export("table").Download(context.Background(), &buf, WithResume())
+	if err == nil {
+		t.Fatalf("expected an error combining WithResume without WithoutDecompression")
+	}
+}
+
+func TestExportQueryDownloadSeeksToEndOnResume(t *testing.T) {
+	raw := []byte("col1,col2\nval1,val2\nval3,val4\nval5,val6\n")
+	gz := gzipBytes(t, raw)
+
+	server := newExportTestServer(t, gz)
+	defer server.Close()
+
+	client := NewClient("testkey", WithBaseURL(server.URL), WithHTTPClient(server.Client()))
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "export.csv.gz")
+
+	partial := gz[:len(gz)/2]
+	if err := os.WriteFile(path, partial, 0644); err != nil {
+		t.Fatalf("seed partial file: %v", err)
+	}
+
+	// Open the file positioned at its start, as a caller who forgot to seek
+	// before calling Download directly (rather than through DownloadToFile)
+	// would. Download must seek to the end itself before writing the resumed
+	// bytes, or it will overwrite the existing content instead of appending.
+	f, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("open partial file: %v", err)
+	}
+	defer f.Close()
+
+	result, err := client.Export("table").Download(context.Background(), f, WithResume(), WithoutDecompression())
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(got, gz) {
+		t.Fatalf("resumed file does not match the full export: got %d bytes, want %d", len(got), len(gz))
+	}
+	if result.Bytes != int64(len(gz)-len(partial)) {
+		t.Fatalf("Bytes = %d, want %d (only the newly written bytes)", result.Bytes, len(gz)-len(partial))
+	}
+}
+
+func TestExportQueryDownloadToFileResumesPartialDownload(t *testing.T) {
+	raw := []byte("col1,col2\nval1,val2\nval3,val4\nval5,val6\n")
+	gz := gzipBytes(t, raw)
+
+	server := newExportTestServer(t, gz)
+	defer server.Close()
+
+	client := NewClient("testkey", WithBaseURL(server.URL), WithHTTPClient(server.Client()))
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "export.csv.gz")
+
+	// Simulate a previous, interrupted attempt that left a partial .tmp file
+	// behind: DownloadToFile resumes from whatever is already in it.
+	partial := gz[:len(gz)/2]
+	if err := os.WriteFile(path+".tmp", partial, 0644); err != nil {
+		t.Fatalf("seed partial file: %v", err)
+	}
+
+	result, err := client.Export("table").DownloadToFile(context.Background(), path, WithResume(), WithoutDecompression())
+	if err != nil {
+		t.Fatalf("DownloadToFile: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(got, gz) {
+		t.Fatalf("resumed file does not match the full export: got %d bytes, want %d", len(got), len(gz))
+	}
+	if result.Bytes != int64(len(gz)-len(partial)) {
+		t.Fatalf("Bytes = %d, want %d (only the newly written bytes)", result.Bytes, len(gz)-len(partial))
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("expected the .tmp file to be renamed away, stat err = %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package enigma
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitInfo reports the rate-limit quota state returned with a response, as
+// found in the X-RateLimit-* headers. Fields are zero when the corresponding
+// header is absent.
+type RateLimitInfo struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// APIError is returned by a query when the API responds with a non-2xx status. It
+// preserves the status code, the parsed error body, and any rate-limit
+// information the response carried, instead of collapsing everything into a
+// plain error string.
+type APIError struct {
+	StatusCode int
+	Status     string
+	// Code is the API's own error code, from info.rescode.
+	Code string
+	// Message is a human-readable description of the error, from info.additional.
+	Message string
+	// Additional is the raw info.additional field, kept for backward
+	// compatibility with callers matching on it directly.
+	Additional string
+	RequestID  string
+	RateLimit  RateLimitInfo
+	Raw        []byte
+
+	retryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("enigma: %s (%s)", e.Message, e.Status)
+	}
+	return fmt.Sprintf("enigma: %s", e.Status)
+}
+
+// parseAPIError builds an APIError from a non-2xx HTTP response and its already
+// read body.
+func parseAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+		RateLimit:  parseRateLimitInfo(resp.Header),
+		Raw:        body,
+		retryAfter: parseRetryAfter(resp.Header),
+	}
+
+	var parsed struct {
+		Info struct {
+			ResCode    string `json:"rescode"`
+			Additional string `json:"additional"`
+		} `json:"info"`
+	}
+	if json.Unmarshal(body, &parsed) == nil {
+		apiErr.Code = parsed.Info.ResCode
+		apiErr.Additional = parsed.Info.Additional
+		apiErr.Message = parsed.Info.Additional
+	}
+
+	return apiErr
+}
+
+// parseRateLimitInfo reads the X-RateLimit-* headers, if present.
+func parseRateLimitInfo(header http.Header) RateLimitInfo {
+	var info RateLimitInfo
+	if limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil {
+		info.Limit = limit
+	}
+	if remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		info.Remaining = remaining
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		info.Reset = time.Unix(reset, 0)
+	}
+	return info
+}
+
+// IsRateLimited reports whether err is an APIError caused by rate limiting (HTTP
+// 429).
+func IsRateLimited(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// IsNotFound reports whether err is an APIError caused by a missing resource
+// (HTTP 404).
+func IsNotFound(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsAuthError reports whether err is an APIError caused by a missing or invalid
+// API key (HTTP 401 or 403).
+func IsAuthError(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+// RetryAfter returns the delay the server asked callers to wait before retrying
+// err's request, parsed from its Retry-After header. It returns 0 if err isn't an
+// APIError or carried no such header.
+func RetryAfter(err error) time.Duration {
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		return 0
+	}
+	return apiErr.retryAfter
+}
@@ -0,0 +1,124 @@
+package enigma
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newAPIError(t *testing.T, status int, header http.Header, body string) *APIError {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	for key, values := range header {
+		for _, v := range values {
+			recorder.Header().Add(key, v)
+		}
+	}
+	recorder.WriteHeader(status)
+	resp := recorder.Result()
+	return parseAPIError(resp, []byte(body))
+}
+
+func TestParseAPIErrorReadsInfoFields(t *testing.T) {
+	body := `{"info":{"rescode":"bad_request","additional":"missing required parameter"}}`
+	apiErr := newAPIError(t, http.StatusBadRequest, nil, body)
+
+	if apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("StatusCode = %d, want %d", apiErr.StatusCode, http.StatusBadRequest)
+	}
+	if apiErr.Code != "bad_request" {
+		t.Fatalf("Code = %q, want %q", apiErr.Code, "bad_request")
+	}
+	if apiErr.Message != "missing required parameter" {
+		t.Fatalf("Message = %q, want %q", apiErr.Message, "missing required parameter")
+	}
+	if apiErr.Error() == "" {
+		t.Fatalf("Error() returned an empty string")
+	}
+}
+
+func TestParseAPIErrorToleratesNonJSONBody(t *testing.T) {
+	apiErr := newAPIError(t, http.StatusInternalServerError, nil, "not json")
+	if apiErr.Code != "" || apiErr.Message != "" {
+		t.Fatalf("expected Code/Message to stay empty for a non-JSON body, got Code=%q Message=%q", apiErr.Code, apiErr.Message)
+	}
+	if apiErr.Error() == "" {
+		t.Fatalf("Error() returned an empty string")
+	}
+}
+
+func TestParseRateLimitInfo(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "100")
+	header.Set("X-RateLimit-Remaining", "42")
+	header.Set("X-RateLimit-Reset", "1700000000")
+
+	apiErr := newAPIError(t, http.StatusTooManyRequests, header, "{}")
+	if apiErr.RateLimit.Limit != 100 {
+		t.Fatalf("RateLimit.Limit = %d, want 100", apiErr.RateLimit.Limit)
+	}
+	if apiErr.RateLimit.Remaining != 42 {
+		t.Fatalf("RateLimit.Remaining = %d, want 42", apiErr.RateLimit.Remaining)
+	}
+	wantReset := time.Unix(1700000000, 0)
+	if !apiErr.RateLimit.Reset.Equal(wantReset) {
+		t.Fatalf("RateLimit.Reset = %v, want %v", apiErr.RateLimit.Reset, wantReset)
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	apiErr := newAPIError(t, http.StatusTooManyRequests, nil, "{}")
+	if !IsRateLimited(apiErr) {
+		t.Fatalf("expected IsRateLimited to be true for a 429")
+	}
+	if IsRateLimited(newAPIError(t, http.StatusOK, nil, "{}")) {
+		t.Fatalf("expected IsRateLimited to be false for a 200")
+	}
+	if IsRateLimited(nil) {
+		t.Fatalf("expected IsRateLimited to be false for a non-APIError")
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if !IsNotFound(newAPIError(t, http.StatusNotFound, nil, "{}")) {
+		t.Fatalf("expected IsNotFound to be true for a 404")
+	}
+	if IsNotFound(newAPIError(t, http.StatusOK, nil, "{}")) {
+		t.Fatalf("expected IsNotFound to be false for a 200")
+	}
+}
+
+func TestIsAuthError(t *testing.T) {
+	if !IsAuthError(newAPIError(t, http.StatusUnauthorized, nil, "{}")) {
+		t.Fatalf("expected IsAuthError to be true for a 401")
+	}
+	if !IsAuthError(newAPIError(t, http.StatusForbidden, nil, "{}")) {
+		t.Fatalf("expected IsAuthError to be true for a 403")
+	}
+	if IsAuthError(newAPIError(t, http.StatusOK, nil, "{}")) {
+		t.Fatalf("expected IsAuthError to be false for a 200")
+	}
+}
+
+func TestRetryAfterPrefersHeaderOverComputedBackoff(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "7")
+	apiErr := newAPIError(t, http.StatusTooManyRequests, header, "{}")
+
+	if got, want := RetryAfter(apiErr), 7*time.Second; got != want {
+		t.Fatalf("RetryAfter = %v, want %v", got, want)
+	}
+	if got, want := DefaultRetryPolicy().nextDelay(0, RetryAfter(apiErr)), 7*time.Second; got != want {
+		t.Fatalf("nextDelay with the header's RetryAfter = %v, want %v", got, want)
+	}
+}
+
+func TestRetryAfterZeroWithoutHeaderOrNonAPIError(t *testing.T) {
+	if got := RetryAfter(newAPIError(t, http.StatusInternalServerError, nil, "{}")); got != 0 {
+		t.Fatalf("RetryAfter without a Retry-After header = %v, want 0", got)
+	}
+	if got := RetryAfter(nil); got != 0 {
+		t.Fatalf("RetryAfter(nil) = %v, want 0", got)
+	}
+}
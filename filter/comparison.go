@@ -0,0 +1,110 @@
+package filter
+
+import "fmt"
+
+// comparisonFilter is a single "<column><operator><value>" where clause.
+type comparisonFilter struct {
+	column   string
+	operator string
+	value    interface{}
+}
+
+func (f comparisonFilter) Encode() (whereParams, searchParams []string, conjunction Conjunction, err error) {
+	value, err := encodeValue(f.value)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return []string{f.column + f.operator + value}, nil, "", nil
+}
+
+// Gt matches rows where column is greater than value.
+func Gt(column string, value interface{}) Filter {
+	return comparisonFilter{column: column, operator: ">", value: value}
+}
+
+// Gte matches rows where column is greater than or equal to value.
+func Gte(column string, value interface{}) Filter {
+	return comparisonFilter{column: column, operator: ">=", value: value}
+}
+
+// Lt matches rows where column is less than value.
+func Lt(column string, value interface{}) Filter {
+	return comparisonFilter{column: column, operator: "<", value: value}
+}
+
+// Lte matches rows where column is less than or equal to value.
+func Lte(column string, value interface{}) Filter {
+	return comparisonFilter{column: column, operator: "<=", value: value}
+}
+
+// Eq matches rows where column is equal to value.
+func Eq(column string, value interface{}) Filter {
+	return comparisonFilter{column: column, operator: "=", value: value}
+}
+
+// Ne matches rows where column is not equal to value.
+func Ne(column string, value interface{}) Filter {
+	return comparisonFilter{column: column, operator: "!=", value: value}
+}
+
+// betweenFilter is a "<column> [not] between <lo> and <hi>" where clause.
+type betweenFilter struct {
+	column string
+	lo, hi interface{}
+	negate bool
+}
+
+func (f betweenFilter) Encode() (whereParams, searchParams []string, conjunction Conjunction, err error) {
+	lo, err := encodeValue(f.lo)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	hi, err := encodeValue(f.hi)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	clause := fmt.Sprintf("%s between %s and %s", f.column, lo, hi)
+	if f.negate {
+		clause = fmt.Sprintf("%s not between %s and %s", f.column, lo, hi)
+	}
+	return []string{clause}, nil, "", nil
+}
+
+// Between matches rows where column lies within [lo, hi] inclusive.
+func Between(column string, lo, hi interface{}) Filter {
+	return betweenFilter{column: column, lo: lo, hi: hi}
+}
+
+// NotBetween matches rows where column lies outside [lo, hi].
+func NotBetween(column string, lo, hi interface{}) Filter {
+	return betweenFilter{column: column, lo: lo, hi: hi, negate: true}
+}
+
+// inFilter is a "<column> [not] in (<values>)" where clause.
+type inFilter struct {
+	column string
+	values []interface{}
+	negate bool
+}
+
+func (f inFilter) Encode() (whereParams, searchParams []string, conjunction Conjunction, err error) {
+	values, err := encodeValues(f.values)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	clause := f.column + " in " + joinValues(values)
+	if f.negate {
+		clause = f.column + " not in " + joinValues(values)
+	}
+	return []string{clause}, nil, "", nil
+}
+
+// In matches rows where column is one of the given values.
+func In(column string, values ...interface{}) Filter {
+	return inFilter{column: column, values: values}
+}
+
+// NotIn matches rows where column is none of the given values.
+func NotIn(column string, values ...interface{}) Filter {
+	return inFilter{column: column, values: values, negate: true}
+}
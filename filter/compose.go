@@ -0,0 +1,40 @@
+package filter
+
+import "fmt"
+
+// compositeFilter combines the where/search parameters of several filters under a
+// single conjunction.
+type compositeFilter struct {
+	conjunction Conjunction
+	filters     []Filter
+}
+
+// Encode flattens its children's where/search parameters under f.conjunction. The
+// Enigma API only accepts a single conjunction per request, so there's no way to
+// express a child's own conjunction once it's merged into the parent's: nesting
+// And/Or inside one another is rejected rather than silently producing a query
+// that doesn't mean what it looks like it means.
+func (f compositeFilter) Encode() (whereParams, searchParams []string, conjunction Conjunction, err error) {
+	for _, child := range f.filters {
+		if _, ok := child.(compositeFilter); ok {
+			return nil, nil, "", fmt.Errorf("filter: And/Or cannot be nested, since the Enigma API only accepts a single conjunction per request")
+		}
+		where, search, _, err := child.Encode()
+		if err != nil {
+			return nil, nil, "", err
+		}
+		whereParams = append(whereParams, where...)
+		searchParams = append(searchParams, search...)
+	}
+	return whereParams, searchParams, f.conjunction, nil
+}
+
+// And combines filters so that all of them must match.
+func And(filters ...Filter) Filter {
+	return compositeFilter{conjunction: ConjunctionAnd, filters: filters}
+}
+
+// Or combines filters so that any of them may match.
+func Or(filters ...Filter) Filter {
+	return compositeFilter{conjunction: ConjunctionOr, filters: filters}
+}
@@ -0,0 +1,69 @@
+// Package filter provides typed constructors for the where/search query
+// parameters accepted by DataQuery, StatsQuery and ExportQuery, so that callers
+// don't have to hand-assemble SQL-ish string fragments.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Conjunction represents the logical link applied between multiple where/search
+// parameters produced by a composite Filter (see And and Or).
+type Conjunction string
+
+// Valid conjunctions.
+const (
+	ConjunctionAnd Conjunction = "and"
+	ConjunctionOr  Conjunction = "or"
+)
+
+// Filter builds the where and search parameters of a query. Implementations are
+// returned by the package's constructors (Gt, Between, Field, And, ...) and are
+// meant to be passed to DataQuery.Filter, StatsQuery.Filter or ExportQuery.Filter.
+type Filter interface {
+	// Encode returns the raw where and search parameters this filter contributes,
+	// along with the conjunction that should link them when more than one is
+	// present. conjunction is empty when it doesn't matter, e.g. a single
+	// parameter.
+	Encode() (whereParams, searchParams []string, conjunction Conjunction, err error)
+}
+
+// encodeValue formats v the way the Enigma API expects it in a where clause:
+// dates as a date-only RFC3339 date (e.g. "2006-01-02"), strings and
+// fmt.Stringer values quoted, numbers and booleans as-is. Any other type is an
+// error.
+func encodeValue(v interface{}) (string, error) {
+	switch value := v.(type) {
+	case time.Time:
+		return value.Format("2006-01-02"), nil
+	case string:
+		return strconv.Quote(value), nil
+	case fmt.Stringer:
+		return strconv.Quote(value.String()), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
+		return fmt.Sprintf("%v", value), nil
+	default:
+		return "", fmt.Errorf("filter: unsupported value type %T", v)
+	}
+}
+
+// encodeValues formats a list of values for use inside an in()/between() clause.
+func encodeValues(values []interface{}) ([]string, error) {
+	encoded := make([]string, len(values))
+	for i, v := range values {
+		s, err := encodeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = s
+	}
+	return encoded, nil
+}
+
+// joinValues renders a comma-separated, parenthesized list, e.g. "(1,2,3)".
+func joinValues(values []string) string {
+	return "(" + strings.Join(values, ",") + ")"
+}
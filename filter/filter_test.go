@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func encode(t *testing.T, f Filter) ([]string, []string, Conjunction) {
+	t.Helper()
+	where, search, conjunction, err := f.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	return where, search, conjunction
+}
+
+func TestComparisonFilters(t *testing.T) {
+	cases := []struct {
+		name string
+		f    Filter
+		want string
+	}{
+		{"Gt", Gt("age", 21), "age>21"},
+		{"Gte", Gte("age", 21), "age>=21"},
+		{"Lt", Lt("age", 21), "age<21"},
+		{"Lte", Lte("age", 21), "age<=21"},
+		{"Eq", Eq("name", "bob"), `name="bob"`},
+		{"Ne", Ne("name", "bob"), `name!="bob"`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			where, search, conjunction := encode(t, c.f)
+			if !reflect.DeepEqual(where, []string{c.want}) {
+				t.Fatalf("where = %v, want [%q]", where, c.want)
+			}
+			if len(search) != 0 {
+				t.Fatalf("search = %v, want none", search)
+			}
+			if conjunction != "" {
+				t.Fatalf("conjunction = %q, want empty", conjunction)
+			}
+		})
+	}
+}
+
+func TestBetweenFilters(t *testing.T) {
+	where, _, _ := encode(t, Between("age", 18, 65))
+	if !reflect.DeepEqual(where, []string{"age between 18 and 65"}) {
+		t.Fatalf("where = %v", where)
+	}
+
+	where, _, _ = encode(t, NotBetween("age", 18, 65))
+	if !reflect.DeepEqual(where, []string{"age not between 18 and 65"}) {
+		t.Fatalf("where = %v", where)
+	}
+}
+
+func TestInFilters(t *testing.T) {
+	where, _, _ := encode(t, In("state", "NY", "CA"))
+	if !reflect.DeepEqual(where, []string{`state in ("NY","CA")`}) {
+		t.Fatalf("where = %v", where)
+	}
+
+	where, _, _ = encode(t, NotIn("state", "NY", "CA"))
+	if !reflect.DeepEqual(where, []string{`state not in ("NY","CA")`}) {
+		t.Fatalf("where = %v", where)
+	}
+}
+
+func TestFieldMatch(t *testing.T) {
+	where, search, _ := encode(t, Field("name").Match("foo|bar"))
+	if len(where) != 0 {
+		t.Fatalf("where = %v, want none", where)
+	}
+	if !reflect.DeepEqual(search, []string{"@name foo|bar"}) {
+		t.Fatalf("search = %v", search)
+	}
+}
+
+func TestEncodeValueDateIsDateOnly(t *testing.T) {
+	date := time.Date(2020, time.March, 4, 15, 30, 0, 0, time.UTC)
+	where, _, _ := encode(t, Eq("born", date))
+	want := "born=2020-03-04"
+	if !reflect.DeepEqual(where, []string{want}) {
+		t.Fatalf("where = %v, want [%q]", where, want)
+	}
+}
+
+func TestEncodeValueUnsupportedType(t *testing.T) {
+	_, _, _, err := Eq("x", struct{}{}).Encode()
+	if err == nil {
+		t.Fatalf("expected an error for an unsupported value type")
+	}
+}
+
+func TestAndOr(t *testing.T) {
+	where, search, conjunction := encode(t, And(Gt("age", 21), Field("name").Match("bob")))
+	if !reflect.DeepEqual(where, []string{"age>21"}) {
+		t.Fatalf("where = %v", where)
+	}
+	if !reflect.DeepEqual(search, []string{"@name bob"}) {
+		t.Fatalf("search = %v", search)
+	}
+	if conjunction != ConjunctionAnd {
+		t.Fatalf("conjunction = %q, want %q", conjunction, ConjunctionAnd)
+	}
+
+	_, _, conjunction, err := Or(Gt("age", 21), Lt("age", 65)).Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if conjunction != ConjunctionOr {
+		t.Fatalf("conjunction = %q, want %q", conjunction, ConjunctionOr)
+	}
+}
+
+func TestAndOrRejectsNesting(t *testing.T) {
+	_, _, _, err := And(Gt("age", 21), Or(Eq("state", "NY"), Eq("state", "CA"))).Encode()
+	if err == nil {
+		t.Fatalf("expected an error for a composite filter nested inside another")
+	}
+}
+
+func TestAndOrPropagatesChildErrors(t *testing.T) {
+	_, _, _, err := And(Eq("x", struct{}{})).Encode()
+	if err == nil {
+		t.Fatalf("expected the child's encoding error to propagate")
+	}
+}
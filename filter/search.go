@@ -0,0 +1,29 @@
+package filter
+
+// FieldBuilder scopes a full-text search to a single field. Build one with Field.
+type FieldBuilder struct {
+	field string
+}
+
+// Field starts a full-text search scoped to the given field name.
+//
+//	filter.Field("name").Match("foo")
+func Field(name string) FieldBuilder {
+	return FieldBuilder{field: name}
+}
+
+// Match matches rows whose field contains query. Multiple queries can be combined
+// with the "|" (or) operator, e.g. "foo|bar".
+func (b FieldBuilder) Match(query string) Filter {
+	return searchFilter{field: b.field, query: query}
+}
+
+// searchFilter is a "@field query" search clause.
+type searchFilter struct {
+	field string
+	query string
+}
+
+func (f searchFilter) Encode() (whereParams, searchParams []string, conjunction Conjunction, err error) {
+	return nil, []string{"@" + f.field + " " + f.query}, "", nil
+}
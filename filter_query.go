@@ -0,0 +1,51 @@
+package enigma
+
+import "github.com/mohamedattahri/go-enigma/filter"
+
+// applyFilter encodes f and adds its where/search parameters (and conjunction, if
+// more than one parameter is produced) to q. Encoding errors are recorded on q.err
+// and surface the next time the query is executed, since builder methods have no
+// way to return an error of their own.
+func applyFilter(q *query, f filter.Filter) {
+	if q.err != nil {
+		return
+	}
+
+	where, search, conjunction, err := f.Encode()
+	if err != nil {
+		q.err = err
+		return
+	}
+	for _, w := range where {
+		q.params.Add("where", w)
+	}
+	for _, s := range search {
+		q.params.Add("search", s)
+	}
+	if conjunction != "" && len(where)+len(search) > 1 {
+		q.params.Add("conjunction", string(conjunction))
+	}
+}
+
+// Filter adds the where/search parameters produced by f to the query. It's the
+// typed alternative to chaining Where/Search/Conjunction by hand.
+//
+//	client.Data("us.gov.whitehouse.visitor-list").Filter(filter.Gt("total_people", 10))
+func (q *DataQuery) Filter(f filter.Filter) *DataQuery {
+	applyFilter((*query)(q), f)
+	return q
+}
+
+// Filter adds the where/search parameters produced by f to the query. It's the
+// typed alternative to chaining Where/Search/Conjunction by hand.
+func (q *StatsQuery) Filter(f filter.Filter) *StatsQuery {
+	applyFilter((*query)(q), f)
+	return q
+}
+
+// Filter adds the where/search parameters produced by f to the query. It's the
+// typed alternative to chaining Where/Search/Conjunction by hand.
+func (q *ExportQuery) Filter(f filter.Filter) *ExportQuery {
+	applyFilter((*query)(q), f)
+	return q
+}
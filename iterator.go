@@ -0,0 +1,200 @@
+package enigma
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// fetchPageFunc retrieves a single page of rows, along with the total number of
+// pages available, for use by RowIterator.
+type fetchPageFunc func(ctx context.Context, page int) (rows []json.RawMessage, totalPages int, err error)
+
+// pageResult is the payload of a background page fetch.
+type pageResult struct {
+	rows []json.RawMessage
+	err  error
+}
+
+// RowIterator streams the rows of a paginated DataQuery or StatsQuery result one at
+// a time, fetching the next page in the background once the current one is half
+// drained, so that callers rarely block on network I/O between rows.
+//
+//	it := client.Data("us.gov.whitehouse.visitor-list").Iterator(ctx)
+//	defer it.Close()
+//	for it.Next() {
+//		var row VisitorRow
+//		if err := it.Scan(&row); err != nil {
+//			return err
+//		}
+//	}
+//	return it.Err()
+type RowIterator struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	fetch  fetchPageFunc
+
+	page       int
+	totalPages int
+	rows       []json.RawMessage
+	rowIndex   int
+	cur        json.RawMessage
+
+	pending    chan pageResult
+	prefetched bool
+
+	err    error
+	closed bool
+}
+
+// newRowIterator builds a RowIterator over pages produced by fetch, starting at
+// page 1. ctx is derived so that Close cancels any in-flight prefetch.
+func newRowIterator(ctx context.Context, fetch fetchPageFunc) *RowIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	return &RowIterator{ctx: ctx, cancel: cancel, fetch: fetch, page: 1}
+}
+
+// Next advances the iterator to the next row, fetching pages as needed. It returns
+// false when there are no more rows, the context is done, or an error occurred; in
+// the last two cases Err returns the reason.
+func (it *RowIterator) Next() bool {
+	if it.closed || it.err != nil {
+		return false
+	}
+	if ctxErr := it.ctx.Err(); ctxErr != nil {
+		it.err = ctxErr
+		return false
+	}
+
+	for {
+		if it.rowIndex < len(it.rows) {
+			it.cur = it.rows[it.rowIndex]
+			it.rowIndex++
+			if !it.prefetched && it.rowIndex >= (len(it.rows)+1)/2 {
+				it.prefetchNextPage()
+			}
+			return true
+		}
+
+		if it.pending != nil {
+			var result pageResult
+			select {
+			case result = <-it.pending:
+			case <-it.ctx.Done():
+				it.err = it.ctx.Err()
+				return false
+			}
+			it.pending = nil
+			it.prefetched = false
+			if result.err != nil {
+				it.err = result.err
+				return false
+			}
+			it.page++
+			it.rows = result.rows
+			it.rowIndex = 0
+			if len(it.rows) == 0 {
+				return false
+			}
+			continue
+		}
+
+		if it.rows != nil && it.page >= it.totalPages {
+			return false
+		}
+
+		rows, totalPages, err := it.fetch(it.ctx, it.page)
+		if err != nil {
+			if ctxErr := it.ctx.Err(); ctxErr != nil {
+				it.err = ctxErr
+			} else {
+				it.err = err
+			}
+			return false
+		}
+		it.totalPages = totalPages
+		it.rows = rows
+		it.rowIndex = 0
+		if len(rows) == 0 {
+			return false
+		}
+	}
+}
+
+// prefetchNextPage kicks off a background fetch of the page following the one
+// currently being drained, bounded to a single page in flight at a time.
+func (it *RowIterator) prefetchNextPage() {
+	if it.page >= it.totalPages {
+		return
+	}
+	it.prefetched = true
+	pending := make(chan pageResult, 1)
+	it.pending = pending
+	go func(page int) {
+		rows, _, err := it.fetch(it.ctx, page)
+		select {
+		case pending <- pageResult{rows: rows, err: err}:
+		case <-it.ctx.Done():
+		}
+	}(it.page + 1)
+}
+
+// Scan unmarshals the current row into dst, which should be a pointer.
+func (it *RowIterator) Scan(dst interface{}) error {
+	return json.Unmarshal(it.cur, dst)
+}
+
+// Err returns the error, if any, that caused Next to return false. It returns nil
+// if the iterator was simply exhausted or closed normally.
+func (it *RowIterator) Err() error {
+	return it.err
+}
+
+// Close releases resources held by the iterator, aborting any in-flight prefetch.
+// It's safe to call multiple times.
+func (it *RowIterator) Close() {
+	if it.closed {
+		return
+	}
+	it.closed = true
+	it.cancel()
+}
+
+// Iterator returns a RowIterator that streams every row matched by q, fetching
+// pages transparently as it goes.
+func (q *DataQuery) Iterator(ctx context.Context) *RowIterator {
+	return newRowIterator(ctx, func(ctx context.Context, page int) ([]json.RawMessage, int, error) {
+		wire := cloneQuery((*query)(q))
+		wire.params.Set("page", strconv.Itoa(page))
+
+		var response DataResponse
+		if err := wire.fetch(ctx, &response); err != nil {
+			return nil, 0, err
+		}
+		var rows []json.RawMessage
+		if err := json.Unmarshal(response.Result, &rows); err != nil {
+			return nil, 0, err
+		}
+		return rows, response.Info.TotalPages, nil
+	})
+}
+
+// FrequencyIterator returns a RowIterator that streams the frequency buckets of a
+// Frequency stats query, fetching pages transparently as it goes. Scan unmarshals
+// each row into a struct with Value and Count fields.
+func (q *StatsQuery) FrequencyIterator(ctx context.Context) *RowIterator {
+	return newRowIterator(ctx, func(ctx context.Context, page int) ([]json.RawMessage, int, error) {
+		wire := cloneQuery((*query)(q))
+		wire.params.Set("page", strconv.Itoa(page))
+
+		var response *StatsResponse
+		if err := wire.fetch(ctx, &response); err != nil {
+			return nil, 0, err
+		}
+		var rows []json.RawMessage
+		if err := json.Unmarshal(response.Result, &rows); err != nil {
+			return nil, 0, err
+		}
+		return rows, response.Info.TotalPages, nil
+	})
+}
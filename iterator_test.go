@@ -0,0 +1,155 @@
+package enigma
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRowIteratorStreamsAllRowsAcrossPages(t *testing.T) {
+	pages := map[int][]json.RawMessage{
+		1: {json.RawMessage(`{"n":1}`), json.RawMessage(`{"n":2}`)},
+		2: {json.RawMessage(`{"n":3}`)},
+	}
+
+	var mu sync.Mutex
+	calls := map[int]int{}
+	fetch := func(ctx context.Context, page int) ([]json.RawMessage, int, error) {
+		mu.Lock()
+		calls[page]++
+		mu.Unlock()
+		return pages[page], len(pages), nil
+	}
+
+	it := newRowIterator(context.Background(), fetch)
+	defer it.Close()
+
+	var got []int
+	for it.Next() {
+		var row struct {
+			N int `json:"n"`
+		}
+		if err := it.Scan(&row); err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		got = append(got, row.N)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []int{1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("got %v rows, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls[1] != 1 || calls[2] != 1 {
+		t.Fatalf("expected each page fetched exactly once, got %v", calls)
+	}
+}
+
+func TestRowIteratorPropagatesFetchError(t *testing.T) {
+	wantErr := errors.New("boom")
+	fetch := func(ctx context.Context, page int) ([]json.RawMessage, int, error) {
+		if page == 1 {
+			return []json.RawMessage{json.RawMessage(`{}`)}, 2, nil
+		}
+		return nil, 0, wantErr
+	}
+
+	it := newRowIterator(context.Background(), fetch)
+	defer it.Close()
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 row before the error, got %d", count)
+	}
+	if it.Err() != wantErr {
+		t.Fatalf("Err() = %v, want %v", it.Err(), wantErr)
+	}
+}
+
+func TestRowIteratorStopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	unblock := make(chan struct{})
+	fetch := func(ctx context.Context, page int) ([]json.RawMessage, int, error) {
+		if page == 1 {
+			// A large totalPages keeps page 1 from looking exhausted, so Next
+			// schedules a prefetch of page 2 that blocks until cancellation.
+			return []json.RawMessage{json.RawMessage(`{}`)}, 5, nil
+		}
+		select {
+		case <-unblock:
+		case <-ctx.Done():
+		}
+		return nil, 0, ctx.Err()
+	}
+
+	it := newRowIterator(ctx, fetch)
+	defer it.Close()
+
+	if !it.Next() {
+		t.Fatalf("expected the first row")
+	}
+
+	cancel()
+	close(unblock)
+
+	if it.Next() {
+		t.Fatalf("expected iteration to stop after cancellation")
+	}
+	if it.Err() == nil {
+		t.Fatalf("expected a context error, got nil")
+	}
+}
+
+func TestRowIteratorClosePreventsFurtherFetches(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	fetch := func(ctx context.Context, page int) ([]json.RawMessage, int, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return []json.RawMessage{json.RawMessage(`{}`)}, 10, nil
+	}
+
+	it := newRowIterator(context.Background(), fetch)
+	if !it.Next() {
+		t.Fatalf("expected the first row")
+	}
+	it.Close()
+
+	// Give any stray prefetch goroutine a chance to observe the cancellation
+	// before asserting.
+	time.Sleep(10 * time.Millisecond)
+
+	mu.Lock()
+	callsAtClose := calls
+	mu.Unlock()
+
+	if it.Next() {
+		t.Fatalf("expected Next to return false after Close")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls < 1 {
+		t.Fatalf("expected at least 1 fetch before Close, got %d", calls)
+	}
+	if calls != callsAtClose {
+		t.Fatalf("expected no fetch after Close, got %d more", calls-callsAtClose)
+	}
+}
@@ -0,0 +1,101 @@
+package enigma
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// ClientOption configures a Client created by NewClient.
+type ClientOption func(*Client)
+
+// WithHTTPClient overrides the *http.Client used to perform requests, e.g. to
+// inject a test double or a client with custom transport/timeout settings.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(client *Client) {
+		client.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(client *Client) {
+		client.userAgent = userAgent
+	}
+}
+
+// WithBaseURL overrides the API's base URL, e.g. to point the client at a test
+// server instead of the production Enigma API.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(client *Client) {
+		client.baseURL = baseURL
+	}
+}
+
+// WithRetry overrides the retry policy applied to network errors and 5xx/429
+// responses. See RetryPolicy for details.
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(client *Client) {
+		client.retry = policy
+	}
+}
+
+// RetryPolicy describes how a query is retried on transient failures: network
+// errors and 5xx/429 responses. Delays grow exponentially from BaseDelay, capped at
+// MaxDelay, with jitter applied to avoid retry storms against the API.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a single query,
+	// including the first one. Values below 1 are treated as 1, i.e. no retries.
+	MaxAttempts int
+	// BaseDelay is the upper bound of the jittered delay before the first retry;
+	// the bound doubles with each further retry. Zero means
+	// DefaultRetryPolicy's BaseDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff delay, regardless of attempt number. A
+	// server-provided Retry-After delay is used as is, even above MaxDelay. Zero
+	// means DefaultRetryPolicy's MaxDelay.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy makes up to 3 attempts at a query (the first one plus up to
+// 2 retries), backing off from a 500ms base delay and capping at 30s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+	}
+}
+
+// attempts normalizes MaxAttempts to a usable value.
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// nextDelay computes the delay before the given attempt's retry. retryAfter, when
+// positive, takes precedence over the computed exponential backoff, honoring a
+// server-provided Retry-After header.
+func (p RetryPolicy) nextDelay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	base := p.BaseDelay
+	if base <= 0 {
+		base = DefaultRetryPolicy().BaseDelay
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryPolicy().MaxDelay
+	}
+
+	delay := base << uint(attempt)
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	// Full jitter: a random delay between 0 and the computed cap.
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
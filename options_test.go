@@ -0,0 +1,85 @@
+package enigma
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyAttemptsNormalizesBelowOne(t *testing.T) {
+	cases := []struct {
+		maxAttempts int
+		want        int
+	}{
+		{maxAttempts: 0, want: 1},
+		{maxAttempts: -1, want: 1},
+		{maxAttempts: 1, want: 1},
+		{maxAttempts: 5, want: 5},
+	}
+	for _, c := range cases {
+		p := RetryPolicy{MaxAttempts: c.maxAttempts}
+		if got := p.attempts(); got != c.want {
+			t.Fatalf("RetryPolicy{MaxAttempts: %d}.attempts() = %d, want %d", c.maxAttempts, got, c.want)
+		}
+	}
+}
+
+func TestRetryPolicyNextDelayHonorsRetryAfter(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second}
+
+	retryAfter := 12 * time.Second
+	if got := p.nextDelay(5, retryAfter); got != retryAfter {
+		t.Fatalf("nextDelay with a positive retryAfter = %v, want %v", got, retryAfter)
+	}
+}
+
+func TestRetryPolicyNextDelayIsBoundedByMaxDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 500 * time.Millisecond, MaxDelay: 2 * time.Second}
+
+	// A high attempt number pushes the exponential backoff well past MaxDelay;
+	// nextDelay must still never return more than MaxDelay.
+	for attempt := 0; attempt < 10; attempt++ {
+		if got := p.nextDelay(attempt, 0); got > p.MaxDelay {
+			t.Fatalf("nextDelay(%d, 0) = %v, want <= MaxDelay %v", attempt, got, p.MaxDelay)
+		}
+	}
+}
+
+func TestRetryPolicyNextDelayGrowsWithAttempt(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Hour}
+
+	// Full jitter makes any single delay unpredictable, so instead assert on the
+	// ceiling each attempt's delay is drawn from: every draw stays under a ceiling
+	// that doubles with each attempt, and some draw goes above the previous
+	// attempt's ceiling (with 64 draws, missing one is vanishingly unlikely).
+	prevCeiling := time.Duration(0)
+	for attempt := 0; attempt < 5; attempt++ {
+		ceiling := p.BaseDelay << uint(attempt)
+		if ceiling <= prevCeiling {
+			t.Fatalf("expected the backoff ceiling to grow, got %v after %v", ceiling, prevCeiling)
+		}
+
+		exceededPrev := false
+		for i := 0; i < 64; i++ {
+			got := p.nextDelay(attempt, 0)
+			if got > ceiling {
+				t.Fatalf("nextDelay(%d, 0) = %v, want <= %v", attempt, got, ceiling)
+			}
+			if got > prevCeiling {
+				exceededPrev = true
+			}
+		}
+		if !exceededPrev {
+			t.Fatalf("nextDelay(%d, 0) never exceeded the previous ceiling %v", attempt, prevCeiling)
+		}
+		prevCeiling = ceiling
+	}
+}
+
+func TestRetryPolicyNextDelayFallsBackToDefaultsWhenUnset(t *testing.T) {
+	var p RetryPolicy // zero value: BaseDelay and MaxDelay unset
+
+	got := p.nextDelay(0, 0)
+	if got < 0 || got > DefaultRetryPolicy().BaseDelay {
+		t.Fatalf("nextDelay(0, 0) with a zero-value RetryPolicy = %v, want between 0 and %v", got, DefaultRetryPolicy().BaseDelay)
+	}
+}
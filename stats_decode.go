@@ -0,0 +1,180 @@
+package enigma
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// FrequencyBucket is one value/count pair of a Frequency stats result.
+type FrequencyBucket struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// CompoundBucket is one key/value pair of a By/Of compound stats result.
+type CompoundBucket struct {
+	Key   string  `json:"key"`
+	Value float64 `json:"value"`
+}
+
+// Stats is the typed decoding of a StatsResponse.Result, populated according to
+// the operations that were requested. Pointer fields are nil when the
+// corresponding operation wasn't requested.
+type Stats struct {
+	Sum      *float64
+	Avg      *float64
+	StdDev   *float64
+	Variance *float64
+	Min      *float64
+	Max      *float64
+
+	// MinTime and MaxTime hold the Min/Max result instead of Min/Max above when
+	// Decode is called with WithColumnType("date"), since the API reports a date
+	// column's Min/Max as a date rather than a number.
+	MinTime *time.Time
+	MaxTime *time.Time
+
+	// Frequency holds the result of a Frequency operation.
+	Frequency []FrequencyBucket
+
+	// Compound holds the result of a By/Of compound operation.
+	Compound []CompoundBucket
+}
+
+// scalarStatsOperations are the operations whose result is a single number keyed
+// by operation name in the response body.
+var scalarStatsOperations = []Operation{Sum, Avg, StdDev, Variance, Min, Max}
+
+// dateLayout is the format the API uses for a date column's Min/Max result, the
+// same date-only layout filter.encodeValue writes where clauses in.
+const dateLayout = "2006-01-02"
+
+// decodeConfig holds the options accumulated from DecodeOption values.
+type decodeConfig struct {
+	dateColumn bool
+}
+
+// DecodeOption configures StatsResponse.Decode.
+type DecodeOption func(*decodeConfig)
+
+// WithColumnType tells Decode how to read the Min/Max result: the API reports it
+// as a number for a numerical column, but as a date string for a date column (see
+// StatsQuery.Operation). Pass "date" for a date column; Decode defaults to
+// treating Min/Max as numbers otherwise.
+func WithColumnType(columnType string) DecodeOption {
+	return func(c *decodeConfig) {
+		c.dateColumn = columnType == "date"
+	}
+}
+
+// Decode parses r.Result according to r.Info.Operations: scalar operations (Sum,
+// Avg, StdDev, Variance, Min, Max) are read from an object keyed by operation
+// name, Frequency is read as a list of FrequencyBucket, and a By/Of compound
+// query - which reports no named operations - is read as a list of
+// CompoundBucket.
+//
+// Min and Max are read as numbers unless WithColumnType("date") is passed, since
+// a date column's Min/Max is reported as a date string rather than a number - see
+// StatsQuery.Operation.
+func (r *StatsResponse) Decode(opts ...DecodeOption) (Stats, error) {
+	var stats Stats
+
+	cfg := &decodeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if len(r.Info.Operations) == 0 {
+		var rows []CompoundBucket
+		if err := json.Unmarshal(r.Result, &rows); err != nil {
+			return stats, err
+		}
+		stats.Compound = rows
+		return stats, nil
+	}
+
+	requested := make(map[Operation]bool, len(r.Info.Operations))
+	for _, op := range r.Info.Operations {
+		requested[op] = true
+	}
+
+	if requested[Frequency] {
+		if err := json.Unmarshal(r.Result, &stats.Frequency); err != nil {
+			return stats, err
+		}
+	}
+
+	needsScalar := false
+	for _, op := range scalarStatsOperations {
+		if requested[op] {
+			needsScalar = true
+			break
+		}
+	}
+	if needsScalar && cfg.dateColumn {
+		var raw map[string]string
+		if err := json.Unmarshal(r.Result, &raw); err != nil {
+			return stats, err
+		}
+		for _, op := range []Operation{Min, Max} {
+			if !requested[op] {
+				continue
+			}
+			value, ok := raw[string(op)]
+			if !ok {
+				continue
+			}
+			t, err := time.Parse(dateLayout, value)
+			if err != nil {
+				return stats, err
+			}
+			stats.setTime(op, &t)
+		}
+	} else if needsScalar {
+		var raw map[string]float64
+		if err := json.Unmarshal(r.Result, &raw); err != nil {
+			return stats, err
+		}
+		for _, op := range scalarStatsOperations {
+			if !requested[op] {
+				continue
+			}
+			if value, ok := raw[string(op)]; ok {
+				v := value
+				stats.set(op, &v)
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// set assigns value to the Stats field matching op. op must be one of
+// scalarStatsOperations.
+func (s *Stats) set(op Operation, value *float64) {
+	switch op {
+	case Sum:
+		s.Sum = value
+	case Avg:
+		s.Avg = value
+	case StdDev:
+		s.StdDev = value
+	case Variance:
+		s.Variance = value
+	case Min:
+		s.Min = value
+	case Max:
+		s.Max = value
+	}
+}
+
+// setTime assigns value to the MinTime/MaxTime field matching op. op must be Min
+// or Max, the only scalar operations valid on a date column.
+func (s *Stats) setTime(op Operation, value *time.Time) {
+	switch op {
+	case Min:
+		s.MinTime = value
+	case Max:
+		s.MaxTime = value
+	}
+}
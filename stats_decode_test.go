@@ -0,0 +1,137 @@
+package enigma
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestStatsResponseDecodeScalarOperations(t *testing.T) {
+	r := &StatsResponse{
+		Result: json.RawMessage(`{"sum":10,"avg":2.5,"stddev":1.1,"variance":1.21,"min":1,"max":4}`),
+	}
+	r.Info.Operations = []Operation{Sum, Avg, StdDev, Variance, Min, Max}
+
+	stats, err := r.Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		got  *float64
+		want float64
+	}{
+		{"Sum", stats.Sum, 10},
+		{"Avg", stats.Avg, 2.5},
+		{"StdDev", stats.StdDev, 1.1},
+		{"Variance", stats.Variance, 1.21},
+		{"Min", stats.Min, 1},
+		{"Max", stats.Max, 4},
+	}
+	for _, c := range cases {
+		if c.got == nil {
+			t.Fatalf("%s = nil, want %v", c.name, c.want)
+		}
+		if *c.got != c.want {
+			t.Fatalf("%s = %v, want %v", c.name, *c.got, c.want)
+		}
+	}
+	if stats.MinTime != nil || stats.MaxTime != nil {
+		t.Fatalf("expected MinTime/MaxTime to stay nil for a numerical column")
+	}
+}
+
+func TestStatsResponseDecodeOnlyRequestedOperationsAreSet(t *testing.T) {
+	r := &StatsResponse{
+		Result: json.RawMessage(`{"sum":10,"min":1,"max":4}`),
+	}
+	r.Info.Operations = []Operation{Sum}
+
+	stats, err := r.Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if stats.Sum == nil || *stats.Sum != 10 {
+		t.Fatalf("Sum = %v, want 10", stats.Sum)
+	}
+	if stats.Min != nil || stats.Max != nil {
+		t.Fatalf("expected Min/Max to stay nil when not requested, got Min=%v Max=%v", stats.Min, stats.Max)
+	}
+}
+
+func TestStatsResponseDecodeFrequency(t *testing.T) {
+	r := &StatsResponse{
+		Result: json.RawMessage(`[{"value":"NY","count":3},{"value":"CA","count":1}]`),
+	}
+	r.Info.Operations = []Operation{Frequency}
+
+	stats, err := r.Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := []FrequencyBucket{{Value: "NY", Count: 3}, {Value: "CA", Count: 1}}
+	if len(stats.Frequency) != len(want) {
+		t.Fatalf("Frequency = %v, want %v", stats.Frequency, want)
+	}
+	for i := range want {
+		if stats.Frequency[i] != want[i] {
+			t.Fatalf("Frequency[%d] = %v, want %v", i, stats.Frequency[i], want[i])
+		}
+	}
+}
+
+func TestStatsResponseDecodeCompound(t *testing.T) {
+	r := &StatsResponse{
+		Result: json.RawMessage(`[{"key":"NY","value":12.5},{"key":"CA","value":7}]`),
+	}
+
+	stats, err := r.Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := []CompoundBucket{{Key: "NY", Value: 12.5}, {Key: "CA", Value: 7}}
+	if len(stats.Compound) != len(want) {
+		t.Fatalf("Compound = %v, want %v", stats.Compound, want)
+	}
+	for i := range want {
+		if stats.Compound[i] != want[i] {
+			t.Fatalf("Compound[%d] = %v, want %v", i, stats.Compound[i], want[i])
+		}
+	}
+}
+
+func TestStatsResponseDecodeDateColumnMinMax(t *testing.T) {
+	r := &StatsResponse{
+		Result: json.RawMessage(`{"min":"2020-01-01","max":"2020-12-31"}`),
+	}
+	r.Info.Operations = []Operation{Min, Max}
+
+	stats, err := r.Decode(WithColumnType("date"))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	wantMin := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	wantMax := time.Date(2020, time.December, 31, 0, 0, 0, 0, time.UTC)
+	if stats.MinTime == nil || !stats.MinTime.Equal(wantMin) {
+		t.Fatalf("MinTime = %v, want %v", stats.MinTime, wantMin)
+	}
+	if stats.MaxTime == nil || !stats.MaxTime.Equal(wantMax) {
+		t.Fatalf("MaxTime = %v, want %v", stats.MaxTime, wantMax)
+	}
+	if stats.Min != nil || stats.Max != nil {
+		t.Fatalf("expected Min/Max to stay nil for a date column, got Min=%v Max=%v", stats.Min, stats.Max)
+	}
+}
+
+func TestStatsResponseDecodeWithoutColumnTypeFailsOnDateMinMax(t *testing.T) {
+	r := &StatsResponse{
+		Result: json.RawMessage(`{"min":"2020-01-01","max":"2020-12-31"}`),
+	}
+	r.Info.Operations = []Operation{Min, Max}
+
+	if _, err := r.Decode(); err == nil {
+		t.Fatalf("expected Decode to fail to unmarshal a date string as a number without WithColumnType(\"date\")")
+	}
+}